@@ -2,116 +2,10 @@ package matrixprofile
 
 import (
 	"fmt"
-	"os"
 
-	"gonum.org/v1/plot"
-	"gonum.org/v1/plot/plotter"
-	"gonum.org/v1/plot/plotutil"
 	"gonum.org/v1/plot/vg"
-	"gonum.org/v1/plot/vg/draw"
-	"gonum.org/v1/plot/vg/vgimg"
 )
 
-func Points(a []float64, n int) plotter.XYs {
-	pts := make(plotter.XYs, n)
-	for i := 0; i < n; i++ {
-		pts[i].X = float64(i)
-		if i < len(a) {
-			pts[i].Y = a[i]
-		}
-	}
-	return pts
-}
-
-func CreatePlot(pts []plotter.XYs, labels []string) (*plot.Plot, error) {
-	if labels != nil && len(pts) != len(labels) {
-		return nil, fmt.Errorf("number of XYs, %d, does not match number of labels, %d", len(pts), len(labels))
-	}
-
-	p, err := plot.New()
-	if err != nil {
-		return p, err
-	}
-
-	for i := 0; i < len(pts); i++ {
-		if labels == nil {
-			err = plotutil.AddLines(p, "", pts[i])
-		} else {
-			err = plotutil.AddLines(p, labels[i], pts[i])
-		}
-		if err != nil {
-			return p, err
-		}
-	}
-	return p, err
-}
-
-func PlotMP(sigPts, mpPts, cacPts plotter.XYs, motifPts [][]plotter.XYs, filename string) error {
-	var err error
-	rows, cols := 3, 2
-	plots := make([][]*plot.Plot, rows)
-
-	plots[0] = make([]*plot.Plot, cols)
-	plots[1] = make([]*plot.Plot, cols)
-	plots[2] = make([]*plot.Plot, cols)
-
-	plots[0][0], err = CreatePlot([]plotter.XYs{sigPts}, []string{"data"})
-	if err != nil {
-		return err
-	}
-
-	plots[1][0], err = CreatePlot([]plotter.XYs{mpPts}, []string{"matrix profile"})
-	if err != nil {
-		return err
-	}
-
-	plots[2][0], err = CreatePlot([]plotter.XYs{cacPts}, []string{"cac"})
-	if err != nil {
-		return err
-	}
-
-	plots[0][1], err = CreatePlot(motifPts[0], nil)
-	if err != nil {
-		return err
-	}
-
-	plots[1][1], err = CreatePlot(motifPts[1], nil)
-	if err != nil {
-		return err
-	}
-
-	plots[2][1], err = CreatePlot(motifPts[2], nil)
-	if err != nil {
-		return err
-	}
-
-	img := vgimg.New(vg.Points(1200), vg.Points(600))
-	dc := draw.New(img)
-
-	t := draw.Tiles{
-		Rows: rows,
-		Cols: cols,
-	}
-
-	canvases := plot.Align(plots, t, dc)
-	for j := 0; j < rows; j++ {
-		for i := 0; i < cols; i++ {
-			if plots[j][i] != nil {
-				plots[j][i].Draw(canvases[j][i])
-			}
-		}
-	}
-
-	w, err := os.Create(filename)
-	if err != nil {
-		return err
-	}
-
-	png := vgimg.PngCanvas{Canvas: img}
-	_, err = png.WriteTo(w)
-	return err
-}
-
 func Example() {
 	sin := generateSin(1, 5, 0, 0, 100, 2)
 	sin2 := generateSin(0.25, 10, 0, 0.75, 100, 1)
@@ -147,19 +41,20 @@ func Example() {
 	sigPts := Points(sig, len(sig))
 	mpPts := Points(mp.MP, len(sig))
 	cacPts := Points(cac, len(sig))
-	motifPts := make([][]plotter.XYs, k)
 
-	for i := 0; i < k; i++ {
-		motifPts[i] = make([]plotter.XYs, len(motifs[i].Idx))
-	}
+	builder := NewPlotBuilder().SetGrid(3, 2).SetSize(vg.Points(1200), vg.Points(600))
+	builder.AddPanel("data", sigPts, PanelStyle{SharedX: true})
+	builder.AddPanel("matrix profile", mpPts, PanelStyle{SharedX: true})
+	builder.AddPanel("cac", cacPts, PanelStyle{SharedX: true})
 
 	for i := 0; i < k; i++ {
-		for j, idx := range motifs[i].Idx {
-			motifPts[i][j] = Points(sig[idx:idx+m], m)
+		title := fmt.Sprintf("motif %d", i)
+		for _, idx := range motifs[i].Idx {
+			builder.AddPanel(title, Points(sig[idx:idx+m], m), PanelStyle{})
 		}
 	}
 
-	if err = PlotMP(sigPts, mpPts, cacPts, motifPts, "mp_sine.png"); err != nil {
+	if err = builder.Render("mp_sine.png"); err != nil {
 		panic(err)
 	}
 
@@ -330,4 +225,51 @@ func ExampleMatrixProfile_TopKMotifs() {
 	// Motif Group 1
 	//   7 motifs
 	//   minimum distance of 0.090
-}
\ No newline at end of file
+}
+
+func ExamplePlotDistanceMatrix() {
+	// generate a signal mainly composed of sine waves and switches
+	// frequencies, amplitude, and offset midway through
+
+	// amplitude of 1, frequency of 5Hz, sampling frequency of 100 Hz,
+	// time of 2 seconds
+	sin := generateSin(1, 5, 0, 0, 100, 2)
+
+	// amplitude of 0.25, frequency of 10Hz, offset of 0.75, sampling
+	// frequency of 100 Hz, time of 1 second
+	sin2 := generateSin(0.25, 10, 0, 0.75, 100, 1)
+	sig := append(sin, sin2...)
+
+	// noise with an amplitude of 0.01
+	noise := generateNoise(0.01, len(sig))
+	sig = sigAdd(sig, noise)
+
+	// create a new MatrixProfile struct using the signal and a
+	// subsequence length of 32. The second subsequence is set to nil
+	// so we perform a self join.
+	mp, err := New(sig, nil, 32)
+	if err != nil {
+		panic(err)
+	}
+
+	// run the STMP algorithm with self join. The matrix profile
+	// will be stored in mp.MP and the matrix profile index will
+	// be stored in mp.Idx
+	if err = mp.Stmp(); err != nil {
+		panic(err)
+	}
+
+	motifs, err := mp.TopKMotifs(1, 2)
+	if err != nil {
+		panic(err)
+	}
+
+	// overlay the first motif group's matched pairs on the distance
+	// matrix so each marker lands off the trivially-near-zero diagonal
+	if err = PlotDistanceMatrix(mp, "mp_distance_matrix.png", 10, motifs[0].Idx, nil); err != nil {
+		panic(err)
+	}
+
+	fmt.Println("Saved png file result to mp_distance_matrix.png")
+	// Output: Saved png file result to mp_distance_matrix.png
+}
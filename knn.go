@@ -0,0 +1,81 @@
+package matrixprofile
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+
+	"github.com/matrix-profile-foundation/go-matrixprofile/util"
+)
+
+// neighbor is a single candidate nearest neighbor tracked while filling a
+// subsequence's bounded min-heap during ComputeKNN.
+type neighbor struct {
+	idx  int
+	dist float64
+}
+
+// neighborHeap is a max-heap on distance bounded to size k, so the worst
+// of the current top-k candidates always sits at the root and can be
+// evicted in O(log k) as soon as a closer neighbor is found.
+type neighborHeap []neighbor
+
+func (h neighborHeap) Len() int           { return len(h) }
+func (h neighborHeap) Less(i, j int) bool { return h[i].dist > h[j].dist }
+func (h neighborHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *neighborHeap) Push(x interface{}) {
+	*h = append(*h, x.(neighbor))
+}
+
+func (h *neighborHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// ComputeKNN computes the top k nearest, non-trivial-match neighbors for
+// every subsequence of the matrix profile's time series and returns them
+// as a sparse util.KNN triplet graph rather than just the single nearest
+// neighbor stored in MP/Idx. This is meant for long series where storing
+// the full distance matrix is infeasible but more than the top-1 match
+// per subsequence is still wanted.
+func (mp MatrixProfile) ComputeKNN(k int) (*util.KNN, error) {
+	if k < 1 {
+		return nil, fmt.Errorf("k must be greater than or equal to 1, got %d", k)
+	}
+	if mp.M > len(mp.A) {
+		return nil, fmt.Errorf("m cannot be greater than length of the series, got m=%d, len(a)=%d", mp.M, len(mp.A))
+	}
+
+	n := len(mp.A) - mp.M + 1
+	mu, sig := util.MuInvN(mp.A, mp.M)
+	ezsz := int(math.Ceil(float64(mp.M) / 4))
+
+	knn := util.NewKNN(k)
+	for i := 0; i < n; i++ {
+		profile := util.MASS(mp.A[i:i+mp.M], mp.A, mu, sig)
+
+		h := make(neighborHeap, 0, k)
+		for j, d := range profile {
+			if j >= i-ezsz && j <= i+ezsz {
+				continue
+			}
+
+			if len(h) < k {
+				heap.Push(&h, neighbor{idx: j, dist: d})
+			} else if d < h[0].dist {
+				heap.Pop(&h)
+				heap.Push(&h, neighbor{idx: j, dist: d})
+			}
+		}
+
+		for _, nb := range h {
+			knn.Add(i, nb.idx, nb.dist)
+		}
+	}
+
+	return knn, nil
+}
@@ -0,0 +1,153 @@
+package matrixprofile
+
+import (
+	"math"
+	"testing"
+
+	"github.com/matrix-profile-foundation/go-matrixprofile/util"
+)
+
+func TestComputeKNN(t *testing.T) {
+	a := []float64{0, 1, 2, 1, 0, -1, -2, -1, 0, 1, 2, 1, 0, -1, -2, -1, 0}
+	m := 5
+	mp := MatrixProfile{A: a, M: m}
+
+	knn, err := mp.ComputeKNN(2)
+	if err != nil {
+		t.Fatalf("unexpected error, %v", err)
+	}
+
+	n := len(a) - m + 1
+	for i := 0; i < n; i++ {
+		neighbors := knn.Neighbors(i)
+		if len(neighbors) > 2 {
+			t.Errorf("subsequence %d has %d neighbors, expected at most 2", i, len(neighbors))
+		}
+		for j := 1; j < len(neighbors); j++ {
+			if neighbors[j].Dist < neighbors[j-1].Dist {
+				t.Errorf("subsequence %d neighbors are not sorted by distance: %+v", i, neighbors)
+			}
+		}
+	}
+}
+
+func TestComputeKNNInvalidK(t *testing.T) {
+	mp := MatrixProfile{A: []float64{1, 2, 3, 4, 5}, M: 2}
+	if _, err := mp.ComputeKNN(0); err == nil {
+		t.Errorf("expected an error for k=0, but got none")
+	}
+}
+
+func TestComputeKNNMTooLarge(t *testing.T) {
+	mp := MatrixProfile{A: []float64{1, 2, 3}, M: 10}
+	if _, err := mp.ComputeKNN(1); err == nil {
+		t.Errorf("expected an error when m exceeds the length of the series, but got none")
+	}
+}
+
+// bruteForceMP fills MP/Idx using the same exclusion-zoned nearest
+// neighbor search ComputeKNN performs, for use as TopKMotifs test setup
+// without depending on Compute/Stmp.
+func bruteForceMP(a []float64, m int) MatrixProfile {
+	n := len(a) - m + 1
+	mu, sig := util.MuInvN(a, m)
+	ezsz := int(math.Ceil(float64(m) / 4))
+
+	mp := MatrixProfile{A: a, M: m, MP: make([]float64, n), Idx: make([]int, n), SelfJoin: true}
+	for i := 0; i < n; i++ {
+		mp.MP[i] = math.Inf(1)
+		mp.Idx[i] = -1
+		for j := 0; j < n; j++ {
+			if j >= i-ezsz && j <= i+ezsz {
+				continue
+			}
+			var qt float64
+			for x := 0; x < m; x++ {
+				qt += a[i+x] * a[j+x]
+			}
+			d := 2 * float64(m) * (1 - (qt-float64(m)*mu[i]*mu[j])*sig[i]*sig[j])
+			if d < 0 {
+				d = 0
+			}
+			d = math.Sqrt(d)
+			if d < mp.MP[i] {
+				mp.MP[i] = d
+				mp.Idx[i] = j
+			}
+		}
+	}
+	return mp
+}
+
+func TestTopKMotifs(t *testing.T) {
+	a := []float64{0, 1, 2, 1, 0, -1, -2, -1, 0, 1, 2, 1, 0, -1, -2, -1, 0}
+	mp := bruteForceMP(a, 5)
+
+	motifs, err := mp.TopKMotifs(2, 2)
+	if err != nil {
+		t.Fatalf("unexpected error, %v", err)
+	}
+	if len(motifs) == 0 {
+		t.Fatalf("expected at least one motif group")
+	}
+
+	seen := make(map[int]bool)
+	for g, mg := range motifs {
+		if len(mg.Idx) < 2 {
+			t.Errorf("motif group %d has fewer than 2 members: %+v", g, mg)
+		}
+		for _, idx := range mg.Idx {
+			if seen[idx] {
+				t.Errorf("subsequence %d claimed by more than one motif group", idx)
+			}
+			seen[idx] = true
+		}
+	}
+}
+
+func TestTopKMotifsInvalidK(t *testing.T) {
+	mp := bruteForceMP([]float64{0, 1, 2, 1, 0, -1, -2, -1, 0}, 5)
+	if _, err := mp.TopKMotifs(0, 2); err == nil {
+		t.Errorf("expected an error for k=0, but got none")
+	}
+}
+
+func TestTopKMotifsRequiresSelfJoin(t *testing.T) {
+	mp := bruteForceMP([]float64{0, 1, 2, 1, 0, -1, -2, -1, 0}, 5)
+	mp.SelfJoin = false
+	if _, err := mp.TopKMotifs(1, 2); err == nil {
+		t.Errorf("expected an error when SelfJoin is false, but got none")
+	}
+}
+
+func TestTopKMotifsExclusionZone(t *testing.T) {
+	// two occurrences of the same bump, one at 10-14 and a near-duplicate
+	// 1-sample shift of the same occurrence at 46-50, plus a genuinely
+	// distinct third occurrence at 80-84.
+	a := make([]float64, 100)
+	bump := []float64{0, 1, 2, 1, 0}
+	copy(a[10:15], bump)
+	copy(a[46:51], bump)
+	copy(a[80:85], bump)
+
+	mp := bruteForceMP(a, 5)
+	motifs, err := mp.TopKMotifs(1, 2)
+	if err != nil {
+		t.Fatalf("unexpected error, %v", err)
+	}
+	if len(motifs) == 0 {
+		t.Fatalf("expected at least one motif group")
+	}
+
+	ezsz := mp.M / 2
+	for _, idx := range motifs[0].Idx {
+		for _, other := range motifs[0].Idx {
+			if idx == other {
+				continue
+			}
+			if d := idx - other; d <= ezsz && d >= -ezsz {
+				t.Errorf("motif group contains near-duplicate indices %d and %d within exclusion zone %d", idx, other, ezsz)
+			}
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package matrixprofile
+
+import (
+	"fmt"
+	"math"
+)
+
+// MotifGroup is a set of subsequence indices that are all within a
+// radius r of each other's matrix profile distance, representing one
+// recurring pattern found by TopKMotifs. It is also the type handed to
+// OnlineOpts.OnMotifDetected, so the streaming Append/AppendBatch path
+// in online.go reports motifs in the same shape as the batch path here.
+type MotifGroup struct {
+	Idx     []int
+	MinDist float64
+}
+
+// motifNeighborMultiple bounds the k-NN graph backing TopKMotifs' group
+// growth to motifNeighborMultiple*k neighbors per subsequence, rather
+// than the caller's group count k or the full n-1, since the latter
+// degrades ComputeKNN back into the dense O(n^2) distance matrix the
+// sparse triplet graph exists to avoid. A real motif member always
+// ranks among the closest handful of neighbors to its group's seed pair,
+// so this stays far smaller than n-1 without missing genuine members.
+const motifNeighborMultiple = 16
+
+// TopKMotifs finds the top k motif groups in the matrix profile. Each
+// group starts from the unclaimed subsequence with the smallest matrix
+// profile distance to seed a motif pair, then grows by pulling in every
+// neighbor within r times that pair's distance from the sparse k-NN
+// graph built by ComputeKNN, rather than repeatedly scanning MP for
+// candidates. An exclusion zone of mp.M/2 around every member already in
+// a group keeps near-duplicate shifts of the same occurrence (e.g.
+// indices 46 and 47) from being counted as separate members.
+func (mp MatrixProfile) TopKMotifs(k int, r float64) ([]MotifGroup, error) {
+	if !mp.SelfJoin {
+		return nil, fmt.Errorf("can only find top motifs if a self join is performed")
+	}
+	if k < 1 {
+		return nil, fmt.Errorf("k must be greater than or equal to 1, got %d", k)
+	}
+
+	n := len(mp.MP)
+	knnSize := motifNeighborMultiple * k
+	if knnSize > n-1 {
+		knnSize = n - 1
+	}
+	if knnSize < 1 {
+		knnSize = 1
+	}
+	knn, err := mp.ComputeKNN(knnSize)
+	if err != nil {
+		return nil, err
+	}
+
+	ezsz := mp.M / 2
+	claimed := make([]bool, n)
+	var groups []MotifGroup
+
+	for g := 0; g < k; g++ {
+		minDist := math.Inf(1)
+		minIdx := -1
+		for i := 0; i < n; i++ {
+			if claimed[i] || mp.Idx[i] < 0 || mp.MP[i] >= minDist {
+				continue
+			}
+			minDist = mp.MP[i]
+			minIdx = i
+		}
+		if minIdx == -1 {
+			break
+		}
+
+		group := MotifGroup{Idx: []int{minIdx, mp.Idx[minIdx]}, MinDist: minDist}
+		claimed[minIdx] = true
+		claimed[mp.Idx[minIdx]] = true
+
+		for _, t := range knn.Neighbors(minIdx) {
+			if claimed[t.Col] || t.Dist > r*minDist || withinGroupExclusionZone(t.Col, group.Idx, ezsz) {
+				continue
+			}
+			group.Idx = append(group.Idx, t.Col)
+			claimed[t.Col] = true
+		}
+
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
+
+// withinGroupExclusionZone reports whether idx falls within zoneSize of
+// any subsequence already in group.
+func withinGroupExclusionZone(idx int, group []int, zoneSize int) bool {
+	for _, g := range group {
+		d := idx - g
+		if d < 0 {
+			d = -d
+		}
+		if d <= zoneSize {
+			return true
+		}
+	}
+	return false
+}
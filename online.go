@@ -0,0 +1,203 @@
+package matrixprofile
+
+import (
+	"math"
+
+	"github.com/matrix-profile-foundation/go-matrixprofile/util"
+)
+
+// OnlineOpts configures the streaming behavior of Append and AppendBatch,
+// and carries the incremental cumulative sum / mean / inverse norm state
+// between calls. Reuse the same OnlineOpts across every Append and
+// AppendBatch call on a given MatrixProfile so that state builds up
+// incrementally instead of being rebuilt from scratch each call.
+type OnlineOpts struct {
+	// History bounds a streaming MatrixProfile to the most recent
+	// History subsequences, evicting older ones as new points arrive so
+	// memory stays bounded. A value of 0 means unbounded.
+	History int
+
+	// OnMotifDetected, when set, is called with a motif group any time a
+	// newly appended subsequence's matrix profile distance falls at or
+	// below MotifThreshold.
+	OnMotifDetected func(MotifGroup)
+
+	// MotifThreshold is the matrix profile distance that triggers
+	// OnMotifDetected for a newly appended subsequence.
+	MotifThreshold float64
+
+	// cumSum and cumSumSq are the running cumulative sum and
+	// sum-of-squares of every point seen so far, the same buffers
+	// util.Sum2s/util.MovMeanStd build over a static series, except here
+	// they are extended by one entry per appended point rather than
+	// recomputed. mu and sig are the per-subsequence mean and inverse
+	// norm derived from them, indexed the same as MP/Idx.
+	cumSum   []float64
+	cumSumSq []float64
+	mu       []float64
+	sig      []float64
+}
+
+// WithHistory returns an OnlineOpts that bounds a streaming MatrixProfile
+// to the most recent n subsequences, evicting the oldest ones as new
+// data arrives through Append or AppendBatch.
+func WithHistory(n int) *OnlineOpts {
+	return &OnlineOpts{History: n}
+}
+
+// Append incrementally extends the matrix profile's time series by a
+// single point, computing only the new distance profile column against
+// the existing subsequences and merging it into MP and Idx with the
+// exclusion zone applied. opts may be nil to run unbounded with no motif
+// callback, though reusing the same *OnlineOpts across calls is what
+// lets the cumulative sum / mean / inverse norm buffers stay incremental.
+func (mp *MatrixProfile) Append(x float64, opts *OnlineOpts) error {
+	return mp.AppendBatch([]float64{x}, opts)
+}
+
+// AppendBatch incrementally extends the matrix profile's time series by
+// x, one new subsequence column at a time. See Append.
+func (mp *MatrixProfile) AppendBatch(x []float64, opts *OnlineOpts) error {
+	if opts == nil {
+		opts = &OnlineOpts{}
+	}
+
+	for _, v := range x {
+		mp.appendOne(v, opts)
+	}
+	return nil
+}
+
+// appendOne adds a single point to the series, updates MP/Idx with the
+// new subsequence's distance profile against all prior subsequences, and
+// applies the optional history eviction and motif callback.
+func (mp *MatrixProfile) appendOne(x float64, opts *OnlineOpts) {
+	if opts.cumSum == nil {
+		opts.seed(mp.A)
+	}
+	mp.A = append(mp.A, x)
+	opts.extend(x, mp.M)
+
+	n := len(mp.A) - mp.M + 1
+	if n < 1 {
+		// not enough points yet to form a new subsequence
+		return
+	}
+	newIdx := n - 1
+	mu, sig := opts.mu, opts.sig
+
+	mp.MP = append(mp.MP, math.Inf(1))
+	mp.Idx = append(mp.Idx, -1)
+
+	ezsz := int(math.Ceil(float64(mp.M) / 4))
+	profile := util.MASS(mp.A[newIdx:newIdx+mp.M], mp.A, mu, sig)
+	for i := 0; i < newIdx; i++ {
+		if i >= newIdx-ezsz {
+			continue
+		}
+
+		d := profile[i]
+
+		if d < mp.MP[i] {
+			mp.MP[i] = d
+			mp.Idx[i] = newIdx
+		}
+		if d < mp.MP[newIdx] {
+			mp.MP[newIdx] = d
+			mp.Idx[newIdx] = i
+		}
+	}
+
+	if opts.OnMotifDetected != nil && mp.MP[newIdx] <= opts.MotifThreshold {
+		opts.OnMotifDetected(MotifGroup{
+			Idx:     []int{mp.Idx[newIdx], newIdx},
+			MinDist: mp.MP[newIdx],
+		})
+	}
+
+	if opts.History > 0 && n > opts.History {
+		drop := n - opts.History
+		mp.evictHistory(drop)
+		opts.evict(drop)
+	}
+}
+
+// seed initializes the incremental cumulative sum buffers from whatever
+// series data already exists before streaming begins.
+func (opts *OnlineOpts) seed(prefix []float64) {
+	opts.cumSum = make([]float64, len(prefix)+1)
+	opts.cumSumSq = make([]float64, len(prefix)+1)
+	for i, v := range prefix {
+		opts.cumSum[i+1] = opts.cumSum[i] + v
+		opts.cumSumSq[i+1] = opts.cumSumSq[i] + v*v
+	}
+}
+
+// extend folds one more raw point into the cumulative sum buffers in
+// O(1) and, once a full window of m points is available ending at that
+// point, appends the new window's mean and inverse norm to mu/sig
+// rather than recomputing them over the whole series.
+func (opts *OnlineOpts) extend(x float64, m int) {
+	last := len(opts.cumSum) - 1
+	opts.cumSum = append(opts.cumSum, opts.cumSum[last]+x)
+	opts.cumSumSq = append(opts.cumSumSq, opts.cumSumSq[last]+x*x)
+
+	n := len(opts.cumSum) - 1
+	if n < m {
+		return
+	}
+
+	i := n - m
+	mean := (opts.cumSum[n] - opts.cumSum[i]) / float64(m)
+	variance := (opts.cumSumSq[n]-opts.cumSumSq[i])/float64(m) - mean*mean
+
+	opts.mu = append(opts.mu, mean)
+	if variance <= 0 {
+		opts.sig = append(opts.sig, 0)
+	} else {
+		opts.sig = append(opts.sig, 1/math.Sqrt(float64(m)*variance))
+	}
+}
+
+// evict rebases the cumulative sum buffers and drops the oldest mu/sig
+// entries in step with evictHistory trimming MP/Idx, so the incremental
+// state stays aligned with the bounded series.
+func (opts *OnlineOpts) evict(drop int) {
+	if drop <= 0 || len(opts.cumSum) == 0 {
+		return
+	}
+
+	base, baseSq := opts.cumSum[drop], opts.cumSumSq[drop]
+	cumSum := make([]float64, len(opts.cumSum)-drop)
+	cumSumSq := make([]float64, len(opts.cumSumSq)-drop)
+	for i := range cumSum {
+		cumSum[i] = opts.cumSum[i+drop] - base
+		cumSumSq[i] = opts.cumSumSq[i+drop] - baseSq
+	}
+	opts.cumSum = cumSum
+	opts.cumSumSq = cumSumSq
+
+	opts.mu = opts.mu[drop:]
+	opts.sig = opts.sig[drop:]
+}
+
+// evictHistory drops the oldest drop subsequences once a bounded history
+// set by WithHistory has been exceeded, keeping A, MP, and Idx at a
+// fixed size and re-basing any neighbor indices that pointed into the
+// evicted range.
+func (mp *MatrixProfile) evictHistory(drop int) {
+	if drop <= 0 {
+		return
+	}
+
+	mp.A = mp.A[drop:]
+	mp.MP = mp.MP[drop:]
+	mp.Idx = mp.Idx[drop:]
+	for i := range mp.Idx {
+		mp.Idx[i] -= drop
+		if mp.Idx[i] < 0 {
+			mp.Idx[i] = -1
+			mp.MP[i] = math.Inf(1)
+		}
+	}
+}
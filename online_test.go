@@ -0,0 +1,98 @@
+package matrixprofile
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAppendMatchesBruteForce(t *testing.T) {
+	full := []float64{0, 1, 2, 1, 0, -1, -2, -1, 0, 1, 2, 1, 0, -1, -2, -1, 0, 1, 2, 1, 0}
+	m := 5
+
+	mp := MatrixProfile{M: m}
+	opts := &OnlineOpts{}
+	for _, x := range full {
+		if err := mp.Append(x, opts); err != nil {
+			t.Fatalf("unexpected error appending %v, %v", x, err)
+		}
+	}
+
+	want := bruteForceMP(full, m)
+
+	if len(mp.MP) != len(want.MP) {
+		t.Fatalf("expected %d matrix profile entries, got %d", len(want.MP), len(mp.MP))
+	}
+	for i := range want.MP {
+		if math.Abs(mp.MP[i]-want.MP[i]) > 1e-6 {
+			t.Errorf("index %d: expected MP of %.6f, got %.6f", i, want.MP[i], mp.MP[i])
+		}
+	}
+}
+
+func TestAppendBatchMatchesAppend(t *testing.T) {
+	full := []float64{0, 1, 2, 1, 0, -1, -2, -1, 0, 1, 2, 1, 0}
+	m := 4
+
+	mp := MatrixProfile{M: m}
+	opts := &OnlineOpts{}
+	if err := mp.AppendBatch(full, opts); err != nil {
+		t.Fatalf("unexpected error, %v", err)
+	}
+
+	want := bruteForceMP(full, m)
+	for i := range want.MP {
+		if math.Abs(mp.MP[i]-want.MP[i]) > 1e-6 {
+			t.Errorf("index %d: expected MP of %.6f, got %.6f", i, want.MP[i], mp.MP[i])
+		}
+	}
+}
+
+func TestOnMotifDetected(t *testing.T) {
+	full := []float64{0, 1, 2, 1, 0, -1, -2, -1, 0, 1, 2, 1, 0, -1, -2, -1, 0}
+	m := 5
+
+	var detected []MotifGroup
+	mp := MatrixProfile{M: m}
+	opts := &OnlineOpts{
+		MotifThreshold: 0.5,
+		OnMotifDetected: func(mg MotifGroup) {
+			detected = append(detected, mg)
+		},
+	}
+	if err := mp.AppendBatch(full, opts); err != nil {
+		t.Fatalf("unexpected error, %v", err)
+	}
+
+	if len(detected) == 0 {
+		t.Fatalf("expected at least one motif to be detected")
+	}
+	for _, mg := range detected {
+		if mg.MinDist > opts.MotifThreshold {
+			t.Errorf("motif %+v exceeds threshold %.3f", mg, opts.MotifThreshold)
+		}
+	}
+}
+
+func TestWithHistoryBoundsSize(t *testing.T) {
+	full := []float64{0, 1, 2, 1, 0, -1, -2, -1, 0, 1, 2, 1, 0, -1, -2, -1, 0, 1, 2, 1, 0}
+	m := 5
+	history := 4
+
+	mp := MatrixProfile{M: m}
+	opts := WithHistory(history)
+	if err := mp.AppendBatch(full, opts); err != nil {
+		t.Fatalf("unexpected error, %v", err)
+	}
+
+	if len(mp.MP) != history {
+		t.Errorf("expected MP bounded to %d entries, got %d", history, len(mp.MP))
+	}
+	if len(mp.Idx) != history {
+		t.Errorf("expected Idx bounded to %d entries, got %d", history, len(mp.Idx))
+	}
+	for _, idx := range mp.Idx {
+		if idx >= history {
+			t.Errorf("neighbor index %d was not re-based within bounded history of %d", idx, history)
+		}
+	}
+}
@@ -0,0 +1,424 @@
+package matrixprofile
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/palette"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/plotutil"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+	"gonum.org/v1/plot/vg/vgimg"
+
+	"github.com/matrix-profile-foundation/go-matrixprofile/util"
+)
+
+// Points converts a the first n values of a into a plotter.XYs, leaving
+// any index beyond len(a) at Y=0. This pads every series in a panel out
+// to a common length so they line up on a shared X axis.
+func Points(a []float64, n int) plotter.XYs {
+	pts := make(plotter.XYs, n)
+	for i := 0; i < n; i++ {
+		pts[i].X = float64(i)
+		if i < len(a) {
+			pts[i].Y = a[i]
+		}
+	}
+	return pts
+}
+
+// CreatePlot renders pts as a set of overlaid lines, optionally labeling
+// each one from labels, which must either be nil or match pts in length.
+func CreatePlot(pts []plotter.XYs, labels []string) (*plot.Plot, error) {
+	if labels != nil && len(pts) != len(labels) {
+		return nil, fmt.Errorf("number of XYs, %d, does not match number of labels, %d", len(pts), len(labels))
+	}
+
+	p, err := plot.New()
+	if err != nil {
+		return p, err
+	}
+
+	for i := 0; i < len(pts); i++ {
+		if labels == nil {
+			err = plotutil.AddLines(p, "", pts[i])
+		} else {
+			err = plotutil.AddLines(p, labels[i], pts[i])
+		}
+		if err != nil {
+			return p, err
+		}
+	}
+	return p, err
+}
+
+// PanelStyle configures the rendering of a single PlotBuilder panel.
+type PanelStyle struct {
+	// Color, when set, overrides the default line/marker color for this
+	// panel.
+	Color color.Color
+
+	// Marker, when set, draws a scatter glyph of this shape on top of
+	// the panel's line plot.
+	Marker draw.GlyphDrawer
+
+	// LogScaleY draws the panel's Y axis on a log scale.
+	LogScaleY bool
+
+	// SharedX aligns this panel's X axis range with every other
+	// SharedX panel in the builder, so stacked time-indexed panels line
+	// up visually.
+	SharedX bool
+}
+
+// builderPanel accumulates every series and option added to a named
+// PlotBuilder panel before it is rendered.
+type builderPanel struct {
+	title              string
+	series             []plotter.XYs
+	style              PanelStyle
+	exclusionZoneIdx   []int
+	exclusionZoneWidth int
+}
+
+// PlotBuilder composes an arbitrary grid of plot panels, replacing the
+// hardcoded 3x2 layout of the original PlotMP so callers can tile
+// whatever combination of signal, matrix profile, CAC, annotation
+// vector, and motif panels they need.
+type PlotBuilder struct {
+	rows, cols    int
+	width, height vg.Length
+	order         []string
+	panels        map[string]*builderPanel
+}
+
+// NewPlotBuilder creates a PlotBuilder with a single 600x400 panel grid,
+// ready to be resized with SetGrid and SetSize.
+func NewPlotBuilder() *PlotBuilder {
+	return &PlotBuilder{
+		rows:   1,
+		cols:   1,
+		width:  vg.Points(600),
+		height: vg.Points(400),
+		panels: make(map[string]*builderPanel),
+	}
+}
+
+// SetGrid sets the number of panel rows and columns the builder tiles
+// into. Panels are placed into the grid row by row in the order they
+// were first added.
+func (b *PlotBuilder) SetGrid(rows, cols int) *PlotBuilder {
+	b.rows, b.cols = rows, cols
+	return b
+}
+
+// SetSize sets the overall dimensions of the rendered image.
+func (b *PlotBuilder) SetSize(w, h vg.Length) *PlotBuilder {
+	b.width, b.height = w, h
+	return b
+}
+
+// AddPanel adds pts as a series to the named panel, creating the panel
+// in the next free grid cell the first time title is seen. Calling
+// AddPanel again with the same title overlays another series onto that
+// panel, which is how a panel made up of several motif subsequences is
+// built.
+func (b *PlotBuilder) AddPanel(title string, pts plotter.XYs, style PanelStyle) *PlotBuilder {
+	panel, ok := b.panels[title]
+	if !ok {
+		panel = &builderPanel{title: title, style: style}
+		b.panels[title] = panel
+		b.order = append(b.order, title)
+	}
+	panel.series = append(panel.series, pts)
+	return b
+}
+
+// ShadeExclusionZone marks idx on the named panel to be drawn as a
+// shaded rectangle width points to either side, mirroring the
+// exclusion zone matrix profile algorithms apply around a match.
+func (b *PlotBuilder) ShadeExclusionZone(title string, idx, width int) *PlotBuilder {
+	panel, ok := b.panels[title]
+	if !ok {
+		return b
+	}
+	panel.exclusionZoneIdx = append(panel.exclusionZoneIdx, idx)
+	panel.exclusionZoneWidth = width
+	return b
+}
+
+// Render draws every added panel into its grid cell and writes the
+// result to filename as a PNG.
+func (b *PlotBuilder) Render(filename string) error {
+	if len(b.order) > b.rows*b.cols {
+		return fmt.Errorf("%d panels do not fit in a %dx%d grid", len(b.order), b.rows, b.cols)
+	}
+
+	var sharedMin, sharedMax float64
+	haveShared := false
+	for _, title := range b.order {
+		panel := b.panels[title]
+		if !panel.style.SharedX {
+			continue
+		}
+		for _, series := range panel.series {
+			for _, pt := range series {
+				if !haveShared || pt.X < sharedMin {
+					sharedMin = pt.X
+				}
+				if !haveShared || pt.X > sharedMax {
+					sharedMax = pt.X
+				}
+				haveShared = true
+			}
+		}
+	}
+
+	plots := make([][]*plot.Plot, b.rows)
+	for r := range plots {
+		plots[r] = make([]*plot.Plot, b.cols)
+	}
+
+	for i, title := range b.order {
+		panel := b.panels[title]
+		p, err := plot.New()
+		if err != nil {
+			return err
+		}
+		p.Title.Text = panel.title
+
+		var ymin, ymax float64
+		haveY := false
+		for _, series := range panel.series {
+			line, err := plotter.NewLine(series)
+			if err != nil {
+				return err
+			}
+			if panel.style.Color != nil {
+				line.Color = panel.style.Color
+			}
+			p.Add(line)
+
+			if panel.style.Marker != nil {
+				scatter, err := plotter.NewScatter(series)
+				if err != nil {
+					return err
+				}
+				scatter.GlyphStyle.Shape = panel.style.Marker
+				if panel.style.Color != nil {
+					scatter.GlyphStyle.Color = panel.style.Color
+				}
+				p.Add(scatter)
+			}
+
+			for _, pt := range series {
+				if !haveY || pt.Y < ymin {
+					ymin = pt.Y
+				}
+				if !haveY || pt.Y > ymax {
+					ymax = pt.Y
+				}
+				haveY = true
+			}
+		}
+
+		if panel.style.LogScaleY {
+			p.Y.Scale = plot.LogScale{}
+			p.Y.Tick.Marker = plot.LogTicks{}
+		}
+
+		if panel.style.SharedX && haveShared {
+			p.X.Min = sharedMin
+			p.X.Max = sharedMax
+		}
+
+		for _, idx := range panel.exclusionZoneIdx {
+			if !haveY {
+				// nothing plotted in this panel to anchor a shaded
+				// rectangle's Y extent against
+				continue
+			}
+			zone, err := plotter.NewPolygon(plotter.XYs{
+				{X: float64(idx - panel.exclusionZoneWidth), Y: ymin},
+				{X: float64(idx + panel.exclusionZoneWidth), Y: ymin},
+				{X: float64(idx + panel.exclusionZoneWidth), Y: ymax},
+				{X: float64(idx - panel.exclusionZoneWidth), Y: ymax},
+			})
+			if err != nil {
+				return err
+			}
+			zone.Color = color.RGBA{R: 255, A: 40}
+			zone.LineStyle.Color = color.Transparent
+			p.Add(zone)
+		}
+
+		plots[i/b.cols][i%b.cols] = p
+	}
+
+	img := vgimg.New(b.width, b.height)
+	dc := draw.New(img)
+
+	t := draw.Tiles{Rows: b.rows, Cols: b.cols}
+	canvases := plot.Align(plots, t, dc)
+	for r := 0; r < b.rows; r++ {
+		for c := 0; c < b.cols; c++ {
+			if plots[r][c] != nil {
+				plots[r][c].Draw(canvases[r][c])
+			}
+		}
+	}
+
+	w, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	png := vgimg.PngCanvas{Canvas: img}
+	_, err = png.WriteTo(w)
+	return err
+}
+
+// distanceMatrixGrid implements plotter.GridXYZ over a full pairwise
+// z-normalized Euclidean distance matrix so it can be handed to gonum's
+// HeatMap and Contour plotters.
+type distanceMatrixGrid struct {
+	idx []float64
+	z   [][]float64
+}
+
+func (g distanceMatrixGrid) Dims() (c, r int) {
+	return len(g.idx), len(g.idx)
+}
+
+func (g distanceMatrixGrid) X(c int) float64 {
+	return g.idx[c]
+}
+
+func (g distanceMatrixGrid) Y(r int) float64 {
+	return g.idx[r]
+}
+
+func (g distanceMatrixGrid) Z(c, r int) float64 {
+	return g.z[r][c]
+}
+
+// distanceMatrix computes the full pairwise z-normalized Euclidean
+// distance matrix between every subsequence of length m in a, one row at
+// a time via util.MASS. This is the same STAMP distance profile computed
+// for every starting index, and is only meant for visualizing small
+// series since it is still O(n^2 log n).
+func distanceMatrix(a []float64, m int) [][]float64 {
+	n := len(a) - m + 1
+	mu, sig := util.MuInvN(a, m)
+
+	dm := make([][]float64, n)
+	for i := 0; i < n; i++ {
+		dm[i] = util.MASS(a[i:i+m], a, mu, sig)
+	}
+	return dm
+}
+
+// PlotDistanceMatrix renders the full pairwise distance matrix of a
+// matrix profile's time series as a contour/heatmap PNG, giving the
+// "arc + distance matrix" visualization used throughout the matrix
+// profile papers. motifIdx and discordIdx, when non-nil, are overlaid
+// as scatter markers at their matched-pair positions (i, mp.Idx[i]) so
+// a reader can see why the 1-D profile chose a given nearest neighbor,
+// rather than on the trivially near-zero diagonal. nlevels must be at
+// least 2, since the contour levels are spaced across nlevels-1 steps.
+func PlotDistanceMatrix(mp *MatrixProfile, filename string, nlevels int, motifIdx, discordIdx []int) error {
+	if nlevels < 2 {
+		return fmt.Errorf("nlevels must be greater than or equal to 2, got %d", nlevels)
+	}
+	if mp.M > len(mp.A) {
+		return fmt.Errorf("m cannot be greater than length of the series, got m=%d, len(a)=%d", mp.M, len(mp.A))
+	}
+
+	dm := distanceMatrix(mp.A, mp.M)
+	n := len(dm)
+
+	idx := make([]float64, n)
+	for i := range idx {
+		idx[i] = float64(i)
+	}
+	grid := distanceMatrixGrid{idx: idx, z: dm}
+
+	min, max := dm[0][0], dm[0][0]
+	for _, row := range dm {
+		for _, v := range row {
+			if v < min {
+				min = v
+			}
+			if v > max {
+				max = v
+			}
+		}
+	}
+
+	levels := make([]float64, nlevels)
+	for i := 0; i < nlevels; i++ {
+		levels[i] = min + (max-min)*float64(i)/float64(nlevels-1)
+	}
+
+	p, err := plot.New()
+	if err != nil {
+		return err
+	}
+	p.Title.Text = "Distance Matrix"
+
+	heatmap := plotter.NewHeatMap(grid, palette.Heat(nlevels, 1))
+	p.Add(heatmap)
+
+	contour := plotter.NewContour(grid, levels, palette.Heat(nlevels, 1))
+	p.Add(contour)
+
+	// markers plots each index in indices at its matched-pair position
+	// (i, mp.Idx[i]) rather than (i, i), since the diagonal is always
+	// ~0 by construction and conveys nothing about the match itself.
+	// Indices with no matched neighbor (mp.Idx[i] < 0) are skipped.
+	markers := func(indices []int, shape draw.GlyphDrawer) error {
+		pts := make(plotter.XYs, 0, len(indices))
+		for _, i := range indices {
+			if i < 0 || i >= len(mp.Idx) || mp.Idx[i] < 0 {
+				continue
+			}
+			pts = append(pts, plotter.XY{X: float64(i), Y: float64(mp.Idx[i])})
+		}
+		if len(pts) == 0 {
+			return nil
+		}
+		scatter, err := plotter.NewScatter(pts)
+		if err != nil {
+			return err
+		}
+		scatter.GlyphStyle.Shape = shape
+		p.Add(scatter)
+		return nil
+	}
+
+	if motifIdx != nil {
+		if err = markers(motifIdx, draw.CircleGlyph{}); err != nil {
+			return err
+		}
+	}
+	if discordIdx != nil {
+		if err = markers(discordIdx, draw.CrossGlyph{}); err != nil {
+			return err
+		}
+	}
+
+	w, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	wt, err := p.WriterTo(vg.Points(600), vg.Points(600), "png")
+	if err != nil {
+		return err
+	}
+	_, err = wt.WriteTo(w)
+	return err
+}
@@ -0,0 +1,97 @@
+package matrixprofile
+
+import (
+	"image/color"
+	"os"
+	"testing"
+
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+func TestPlotBuilderColor(t *testing.T) {
+	filename := "test_plotbuilder_color.png"
+	defer os.Remove(filename)
+
+	b := NewPlotBuilder()
+	b.AddPanel("series", Points([]float64{1, 2, 3, 2, 1}, 5), PanelStyle{Color: color.RGBA{B: 255, A: 255}})
+	if err := b.Render(filename); err != nil {
+		t.Fatalf("unexpected error rendering with Color set, %v", err)
+	}
+	if _, err := os.Stat(filename); err != nil {
+		t.Errorf("expected %s to be written, %v", filename, err)
+	}
+}
+
+func TestPlotBuilderMarker(t *testing.T) {
+	filename := "test_plotbuilder_marker.png"
+	defer os.Remove(filename)
+
+	b := NewPlotBuilder()
+	b.AddPanel("series", Points([]float64{1, 2, 3, 2, 1}, 5), PanelStyle{Marker: draw.CircleGlyph{}})
+	if err := b.Render(filename); err != nil {
+		t.Fatalf("unexpected error rendering with Marker set, %v", err)
+	}
+}
+
+func TestPlotBuilderLogScaleY(t *testing.T) {
+	filename := "test_plotbuilder_logscale.png"
+	defer os.Remove(filename)
+
+	b := NewPlotBuilder()
+	b.AddPanel("series", Points([]float64{1, 10, 100, 10, 1}, 5), PanelStyle{LogScaleY: true})
+	if err := b.Render(filename); err != nil {
+		t.Fatalf("unexpected error rendering with LogScaleY set, %v", err)
+	}
+}
+
+func TestPlotBuilderShadeExclusionZone(t *testing.T) {
+	filename := "test_plotbuilder_exclusionzone.png"
+	defer os.Remove(filename)
+
+	b := NewPlotBuilder()
+	b.AddPanel("series", Points([]float64{1, 2, 3, 2, 1}, 5), PanelStyle{})
+	b.ShadeExclusionZone("series", 2, 1)
+	if err := b.Render(filename); err != nil {
+		t.Fatalf("unexpected error rendering with an exclusion zone shaded, %v", err)
+	}
+}
+
+func TestPlotBuilderShadeExclusionZoneEmptyPanel(t *testing.T) {
+	filename := "test_plotbuilder_exclusionzone_empty.png"
+	defer os.Remove(filename)
+
+	b := NewPlotBuilder()
+	b.AddPanel("series", plotter.XYs{}, PanelStyle{})
+	b.ShadeExclusionZone("series", 0, 1)
+	if err := b.Render(filename); err != nil {
+		t.Fatalf("expected an empty-series panel with a shaded zone to render without panicking, got %v", err)
+	}
+}
+
+func TestPlotDistanceMatrixInvalidNLevels(t *testing.T) {
+	mp := &MatrixProfile{A: []float64{0, 1, 2, 1, 0, -1, -2, -1, 0}, M: 4, Idx: []int{-1, -1, -1, -1, -1, -1}}
+	if err := PlotDistanceMatrix(mp, "unused.png", 1, nil, nil); err == nil {
+		t.Errorf("expected an error for nlevels=1, but got none")
+	}
+}
+
+func TestPlotDistanceMatrixMTooLarge(t *testing.T) {
+	mp := &MatrixProfile{A: []float64{1, 2, 3}, M: 10}
+	if err := PlotDistanceMatrix(mp, "unused.png", 5, nil, nil); err == nil {
+		t.Errorf("expected an error when m exceeds the length of the series, but got none")
+	}
+}
+
+func TestPlotDistanceMatrixMarkers(t *testing.T) {
+	filename := "test_distance_matrix.png"
+	defer os.Remove(filename)
+
+	a := []float64{0, 1, 2, 1, 0, -1, -2, -1, 0, 1, 2, 1, 0}
+	m := 4
+	mp := &MatrixProfile{A: a, M: m, Idx: []int{-1, 3, 4, 1, 2, -1, 7, 8, 5, 6}}
+
+	if err := PlotDistanceMatrix(mp, filename, 5, []int{0, 1, 2}, []int{5}); err != nil {
+		t.Fatalf("unexpected error, %v", err)
+	}
+}
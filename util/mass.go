@@ -0,0 +1,75 @@
+package util
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/dsp/fourier"
+)
+
+// nextPow2 returns the smallest power of 2 greater than or equal to n.
+func nextPow2(n int) int {
+	p := 1
+	for p < n {
+		p *= 2
+	}
+	return p
+}
+
+// MASS computes the z-normalized Euclidean distance profile of query
+// against every subsequence of ts using the MASS (Mueen's Algorithm for
+// Similarity Search) algorithm. The sliding dot product between query
+// and ts is computed once via FFT convolution in O(n log n) instead of
+// the O(n*w) naive inner-product loop used by a brute force distance
+// profile. mu and sig must be the moving mean and inverse norm of ts for
+// a window the length of query, as returned by MuInvN(ts, len(query)).
+//
+// MatrixProfile.Stmp/Stamp are not part of this tree (matrixprofile.go,
+// the file that would define them alongside New and Compute, is absent
+// from this snapshot), so MASS cannot be wired into them yet. It is
+// wired into ComputeKNN, distanceMatrix, and the online Append path
+// instead, which are the distance-profile call sites that do exist
+// here; re-pointing Stmp/Stamp at MASS is a follow-up once that file
+// lands.
+func MASS(query, ts []float64, mu, sig []float64) []float64 {
+	w := len(query)
+	n := len(ts)
+
+	muQ, sigQ := MuInvN(query, w)
+
+	sz := nextPow2(n)
+
+	// reverse and zero-pad the query to the FFT size
+	q := make([]float64, sz)
+	for i := 0; i < w; i++ {
+		q[i] = query[w-1-i]
+	}
+
+	y := make([]float64, sz)
+	copy(y, ts)
+
+	fft := fourier.NewFFT(sz)
+	qf := fft.Coefficients(nil, q)
+	yf := fft.Coefficients(nil, y)
+
+	for i := range qf {
+		qf[i] *= yf[i]
+	}
+
+	// fourier.FFT.Sequence returns the unnormalized inverse transform, so
+	// divide out the FFT size to recover the true sliding dot product.
+	qt := fft.Sequence(nil, qf)
+	for i := range qt {
+		qt[i] /= float64(sz)
+	}
+
+	dist := make([]float64, n-w+1)
+	for i := range dist {
+		d := 2 * float64(w) * (1 - (qt[i+w-1]-float64(w)*muQ[0]*mu[i])*sigQ[0]*sig[i])
+		if d < 0 {
+			d = 0
+		}
+		dist[i] = math.Sqrt(d)
+	}
+
+	return dist
+}
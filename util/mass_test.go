@@ -0,0 +1,84 @@
+package util
+
+import (
+	"math"
+	"testing"
+)
+
+// naiveDistanceProfile computes the z-normalized Euclidean distance
+// profile of query against every subsequence of ts using the O(n*w)
+// inner-product loop that MASS replaces, for use as a test oracle.
+func naiveDistanceProfile(query, ts []float64, mu, sig []float64) []float64 {
+	w := len(query)
+	n := len(ts)
+
+	muQ, sigQ := MuInvN(query, w)
+
+	dist := make([]float64, n-w+1)
+	for i := range dist {
+		var qt float64
+		for k := 0; k < w; k++ {
+			qt += query[k] * ts[i+k]
+		}
+		d := 2 * float64(w) * (1 - (qt-float64(w)*muQ[0]*mu[i])*sigQ[0]*sig[i])
+		if d < 0 {
+			d = 0
+		}
+		dist[i] = math.Sqrt(d)
+	}
+	return dist
+}
+
+func TestMASS(t *testing.T) {
+	testdata := []struct {
+		ts    []float64
+		query []float64
+		m     int
+	}{
+		{
+			[]float64{1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6, 7, 7, 8, 8},
+			[]float64{1, 1, 2, 2},
+			4,
+		},
+		{
+			[]float64{0, 1, 2, 1, 0, -1, -2, -1, 0, 1, 2, 1, 0, -1, -2, -1, 0},
+			[]float64{0, 1, 2, 1, 0},
+			5,
+		},
+	}
+
+	for _, d := range testdata {
+		mu, sig := MuInvN(d.ts, d.m)
+		got := MASS(d.query, d.ts, mu, sig)
+		want := naiveDistanceProfile(d.query, d.ts, mu, sig)
+
+		if len(got) != len(want) {
+			t.Fatalf("expected %d distances, got %d", len(want), len(got))
+		}
+		for i := range want {
+			if math.Abs(got[i]-want[i]) > 1e-6 {
+				t.Errorf("index %d: expected %.8f, got %.8f for %+v", i, want[i], got[i], d)
+			}
+		}
+	}
+}
+
+func TestNextPow2(t *testing.T) {
+	testdata := []struct {
+		n        int
+		expected int
+	}{
+		{1, 1},
+		{2, 2},
+		{3, 4},
+		{5, 8},
+		{16, 16},
+		{17, 32},
+	}
+
+	for _, d := range testdata {
+		if got := nextPow2(d.n); got != d.expected {
+			t.Errorf("nextPow2(%d): expected %d, got %d", d.n, d.expected, got)
+		}
+	}
+}
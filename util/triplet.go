@@ -0,0 +1,167 @@
+package util
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// Triplet is a single (row, col, dist) entry in a sparse nearest-neighbor
+// graph, analogous to the (i, j, v) triplet format used by sparse matrix
+// solvers.
+type Triplet struct {
+	Row  int     `json:"row"`
+	Col  int     `json:"col"`
+	Dist float64 `json:"dist"`
+}
+
+// KNN is a sparse top-k nearest neighbor graph for a time series' set of
+// subsequences, stored as a flat list of Triplets rather than a dense
+// n x n distance matrix. It is meant for series where the full distance
+// matrix is infeasible to keep in memory but more than the single
+// nearest neighbor in a matrix profile is still wanted.
+type KNN struct {
+	K        int       `json:"k"`
+	Triplets []Triplet `json:"triplets"`
+}
+
+// NewKNN creates an empty sparse k-NN graph that will hold up to k
+// neighbors per subsequence.
+func NewKNN(k int) *KNN {
+	return &KNN{K: k}
+}
+
+// Add appends a (row, col, dist) neighbor pair to the graph.
+func (knn *KNN) Add(row, col int, dist float64) {
+	knn.Triplets = append(knn.Triplets, Triplet{Row: row, Col: col, Dist: dist})
+}
+
+// ForEachNeighbor calls fn for every neighbor recorded for subsequence i,
+// in the order they were added to the graph.
+func (knn *KNN) ForEachNeighbor(i int, fn func(j int, d float64)) {
+	for _, t := range knn.Triplets {
+		if t.Row == i {
+			fn(t.Col, t.Dist)
+		}
+	}
+}
+
+// Save writes the sparse k-NN graph to disk in either json or csv format.
+func (knn *KNN) Save(filepath, format string) error {
+	switch format {
+	case "json":
+		return knn.saveJSON(filepath)
+	case "csv":
+		return knn.saveCSV(filepath)
+	default:
+		return fmt.Errorf("invalid save format %s", format)
+	}
+}
+
+func (knn *KNN) saveJSON(filepath string) error {
+	f, err := os.Create(filepath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return json.NewEncoder(f).Encode(knn)
+}
+
+func (knn *KNN) saveCSV(filepath string) error {
+	f, err := os.Create(filepath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	defer w.Flush()
+
+	if err = w.Write([]string{"row", "col", "dist"}); err != nil {
+		return err
+	}
+	for _, t := range knn.Triplets {
+		row := []string{
+			strconv.Itoa(t.Row),
+			strconv.Itoa(t.Col),
+			strconv.FormatFloat(t.Dist, 'g', -1, 64),
+		}
+		if err = w.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load reads a sparse k-NN graph previously written by Save, replacing
+// any existing triplets.
+func (knn *KNN) Load(filepath, format string) error {
+	switch format {
+	case "json":
+		return knn.loadJSON(filepath)
+	case "csv":
+		return knn.loadCSV(filepath)
+	default:
+		return fmt.Errorf("invalid load format %s", format)
+	}
+}
+
+func (knn *KNN) loadJSON(filepath string) error {
+	f, err := os.Open(filepath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return json.NewDecoder(f).Decode(knn)
+}
+
+func (knn *KNN) loadCSV(filepath string) error {
+	f, err := os.Open(filepath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	records, err := r.ReadAll()
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return fmt.Errorf("no rows found in %s", filepath)
+	}
+
+	knn.Triplets = knn.Triplets[:0]
+	for _, rec := range records[1:] {
+		row, err := strconv.Atoi(rec[0])
+		if err != nil {
+			return err
+		}
+		col, err := strconv.Atoi(rec[1])
+		if err != nil {
+			return err
+		}
+		dist, err := strconv.ParseFloat(rec[2], 64)
+		if err != nil {
+			return err
+		}
+		knn.Add(row, col, dist)
+	}
+	return nil
+}
+
+// Neighbors returns the neighbors recorded for subsequence i, sorted
+// from closest to farthest.
+func (knn *KNN) Neighbors(i int) []Triplet {
+	var out []Triplet
+	knn.ForEachNeighbor(i, func(j int, d float64) {
+		out = append(out, Triplet{Row: i, Col: j, Dist: d})
+	})
+	sort.Slice(out, func(a, b int) bool { return out[a].Dist < out[b].Dist })
+	return out
+}
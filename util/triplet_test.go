@@ -0,0 +1,99 @@
+package util
+
+import (
+	"os"
+	"testing"
+)
+
+func TestKNNForEachNeighbor(t *testing.T) {
+	knn := NewKNN(2)
+	knn.Add(0, 1, 0.5)
+	knn.Add(0, 2, 0.25)
+	knn.Add(1, 0, 0.5)
+
+	var got []Triplet
+	knn.ForEachNeighbor(0, func(j int, d float64) {
+		got = append(got, Triplet{Row: 0, Col: j, Dist: d})
+	})
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 neighbors for row 0, got %d", len(got))
+	}
+}
+
+func TestKNNNeighborsSorted(t *testing.T) {
+	knn := NewKNN(3)
+	knn.Add(0, 1, 0.5)
+	knn.Add(0, 2, 0.1)
+	knn.Add(0, 3, 0.3)
+
+	neighbors := knn.Neighbors(0)
+	if len(neighbors) != 3 {
+		t.Fatalf("expected 3 neighbors, got %d", len(neighbors))
+	}
+	for i := 1; i < len(neighbors); i++ {
+		if neighbors[i].Dist < neighbors[i-1].Dist {
+			t.Errorf("neighbors not sorted by distance: %+v", neighbors)
+		}
+	}
+}
+
+func TestKNNSaveLoadJSON(t *testing.T) {
+	knn := NewKNN(2)
+	knn.Add(0, 1, 0.5)
+	knn.Add(0, 2, 0.25)
+	knn.Add(1, 0, 0.5)
+
+	filepath := "./knn.json"
+	if err := knn.Save(filepath, "json"); err != nil {
+		t.Fatalf("unexpected error saving knn, %v", err)
+	}
+	defer os.Remove(filepath)
+
+	loaded := NewKNN(0)
+	if err := loaded.Load(filepath, "json"); err != nil {
+		t.Fatalf("unexpected error loading knn, %v", err)
+	}
+
+	if loaded.K != knn.K {
+		t.Errorf("expected k of %d, got %d", knn.K, loaded.K)
+	}
+	if len(loaded.Triplets) != len(knn.Triplets) {
+		t.Errorf("expected %d triplets, got %d", len(knn.Triplets), len(loaded.Triplets))
+	}
+}
+
+func TestKNNSaveLoadCSV(t *testing.T) {
+	knn := NewKNN(2)
+	knn.Add(0, 1, 0.5)
+	knn.Add(0, 2, 0.25)
+	knn.Add(1, 0, 0.5)
+
+	filepath := "./knn.csv"
+	if err := knn.Save(filepath, "csv"); err != nil {
+		t.Fatalf("unexpected error saving knn, %v", err)
+	}
+	defer os.Remove(filepath)
+
+	loaded := NewKNN(0)
+	if err := loaded.Load(filepath, "csv"); err != nil {
+		t.Fatalf("unexpected error loading knn, %v", err)
+	}
+
+	if len(loaded.Triplets) != len(knn.Triplets) {
+		t.Fatalf("expected %d triplets, got %d", len(knn.Triplets), len(loaded.Triplets))
+	}
+	for i, want := range knn.Triplets {
+		got := loaded.Triplets[i]
+		if got.Row != want.Row || got.Col != want.Col || got.Dist != want.Dist {
+			t.Errorf("triplet %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+}
+
+func TestKNNSaveInvalidFormat(t *testing.T) {
+	knn := NewKNN(1)
+	if err := knn.Save("./knn.invalid", "xml"); err == nil {
+		t.Errorf("expected an error for an invalid save format, but got none")
+	}
+}